@@ -0,0 +1,74 @@
+package logger
+
+import (
+	"runtime"
+	"runtime/debug"
+	"strings"
+	"time"
+)
+
+// Clock provides the current time to a Logger. It exists so tests can
+// inject a deterministic clock instead of the real one — the default
+// Logger uses realClock, which just wraps time.Now.
+type Clock interface {
+	Now() time.Time
+}
+
+// realClock is the Clock every Logger uses unless overridden with
+// WithClock.
+type realClock struct{}
+
+func (realClock) Now() time.Time { return time.Now() }
+
+// WithClock returns a derived Logger that reads the current time from
+// clock instead of the real wall clock.
+func (lg *Logger) WithClock(clock Clock) *Logger {
+	var derived = lg.clone()
+	derived.clock = clock
+	return derived
+}
+
+// WithTimeFormat returns a derived Logger whose "timestamp" field is
+// formatted with layout (as accepted by time.Time.Format) instead of the
+// default time.RFC3339Nano.
+func (lg *Logger) WithTimeFormat(layout string) *Logger {
+	var derived = lg.clone()
+	derived.timeFormat = layout
+	return derived
+}
+
+// WithCaller returns a derived Logger that adds "file" and "line" fields
+// populated from runtime.Caller to every entry it logs. skip is the number
+// of additional stack frames to skip past the caller of Log itself, for
+// callers that wrap Log in their own helper(s).
+func (lg *Logger) WithCaller(skip int) *Logger {
+	var derived = lg.clone()
+	derived.callerSkip = skip
+	return derived
+}
+
+// WithStack returns a derived Logger that attaches a trimmed stack trace
+// under the "stack" field to every ERROR and FATAL entry it logs.
+func (lg *Logger) WithStack() *Logger {
+	var derived = lg.clone()
+	derived.captureStack = true
+	return derived
+}
+
+// callerInfo resolves the file and line of the caller of log (that is, Log
+// or one of the per-level helpers), skip frames further up than that.
+func callerInfo(skip int) (file string, line int, ok bool) {
+	_, file, line, ok = runtime.Caller(skip + 2)
+	return file, line, ok
+}
+
+// trimmedStack returns the current goroutine's stack trace, with the
+// leading "goroutine N [state]:" header line removed since the level and
+// message already identify the log entry.
+func trimmedStack() string {
+	var stack = string(debug.Stack())
+	if i := strings.IndexByte(stack, '\n'); i >= 0 {
+		stack = stack[i+1:]
+	}
+	return strings.TrimRight(stack, "\n")
+}
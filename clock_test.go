@@ -0,0 +1,103 @@
+package logger
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestWithCallerReportsHelperCallSite(t *testing.T) {
+	var lg = newTestLogger(t).WithCaller(0)
+
+	var captured map[string]interface{}
+	lg.AddHook(func(_ Level, data map[string]interface{}) {
+		captured = data
+	})
+
+	lg.Info("hello") // the line this test expects "file"/"line" to point at
+
+	file, _ := captured["file"].(string)
+	if strings.HasSuffix(file, "logger.go") {
+		t.Fatalf("expected caller info to point at the test file, got %q (inside the Info helper instead of its caller)", file)
+	}
+	if !strings.HasSuffix(file, "clock_test.go") {
+		t.Fatalf("expected caller info to point at clock_test.go, got %q", file)
+	}
+}
+
+func TestWithCallerReportsDirectLogCallSite(t *testing.T) {
+	var lg = newTestLogger(t).WithCaller(0)
+
+	var captured map[string]interface{}
+	lg.AddHook(func(_ Level, data map[string]interface{}) {
+		captured = data
+	})
+
+	lg.Log(INFO, "message", "hello") // the line this test expects "file"/"line" to point at
+
+	file, _ := captured["file"].(string)
+	if strings.HasSuffix(file, "logger.go") {
+		t.Fatalf("expected caller info to point at the test file, got %q (inside Log instead of its caller)", file)
+	}
+	if !strings.HasSuffix(file, "clock_test.go") {
+		t.Fatalf("expected caller info to point at clock_test.go, got %q", file)
+	}
+}
+
+type fakeClock struct {
+	now time.Time
+}
+
+func (c fakeClock) Now() time.Time { return c.now }
+
+func TestWithClockDrivesTimestamp(t *testing.T) {
+	var path = filepath.Join(t.TempDir(), "test.log")
+	lg, err := New("DEBUG", "file://"+path, "false")
+	if err != nil {
+		t.Fatalf("New: %v", err)
+	}
+	lg = lg.WithClock(fakeClock{now: time.Date(2020, 1, 2, 3, 4, 5, 0, time.UTC)})
+
+	lg.Info("hello")
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("reading log output: %v", err)
+	}
+
+	var entry formatedJSON
+	if err := json.Unmarshal(data, &entry); err != nil {
+		t.Fatalf("unmarshaling logged entry: %v, raw: %q", err, data)
+	}
+	if entry.Timestamp != "2020-01-02T03:04:05Z" {
+		t.Fatalf("expected timestamp driven by the fake clock, got %q", entry.Timestamp)
+	}
+}
+
+func TestWithStackAttachesStackOnErrorAndFatalOnly(t *testing.T) {
+	var lg = newTestLogger(t).WithStack()
+
+	for _, level := range []Level{DEBUG, INFO, WARN} {
+		var captured map[string]interface{}
+		lg.AddHook(func(_ Level, data map[string]interface{}) {
+			captured = data
+		})
+		lg.Log(level, "message", "hello")
+		if _, ok := captured["stack"]; ok {
+			t.Fatalf("expected no \"stack\" field at level %v", level)
+		}
+	}
+
+	var captured map[string]interface{}
+	lg.AddHook(func(_ Level, data map[string]interface{}) {
+		captured = data
+	})
+	lg.Log(ERROR, "message", "hello")
+	stack, ok := captured["stack"].(string)
+	if !ok || stack == "" {
+		t.Fatalf("expected a non-empty \"stack\" field on ERROR, got %#v", captured["stack"])
+	}
+}
@@ -0,0 +1,53 @@
+package logger
+
+import (
+	"context"
+
+	"go.opentelemetry.io/otel/trace"
+)
+
+// contextKey is an unexported type for this package's context keys, so
+// they never collide with keys set by other packages.
+type contextKey int
+
+// fieldsContextKey is the context.Context key ContextWithFields stores its
+// fields map under.
+const fieldsContextKey contextKey = 0
+
+// ContextWithFields returns a copy of ctx carrying fields merged on top of
+// any fields a previous ContextWithFields call already stashed in it. This
+// is how middleware (HTTP, gRPC) injects request-scoped data such as
+// request_id, user_id or tenant, for WithContext to later pick up on any
+// *Logger derived from that context.
+func ContextWithFields(ctx context.Context, fields map[string]interface{}) context.Context {
+	var merged = make(map[string]interface{}, len(fields))
+	for k, v := range FieldsFromContext(ctx) {
+		merged[k] = v
+	}
+	for k, v := range fields {
+		merged[k] = v
+	}
+	return context.WithValue(ctx, fieldsContextKey, merged)
+}
+
+// FieldsFromContext returns the fields previously stashed in ctx by
+// ContextWithFields, or nil if there are none.
+func FieldsFromContext(ctx context.Context) map[string]interface{} {
+	fields, _ := ctx.Value(fieldsContextKey).(map[string]interface{})
+	return fields
+}
+
+// traceFields returns the OpenTelemetry trace correlation fields for ctx's
+// span, or nil if ctx carries no valid span.
+func traceFields(ctx context.Context) map[string]interface{} {
+	var sc = trace.SpanContextFromContext(ctx)
+	if !sc.IsValid() {
+		return nil
+	}
+
+	return map[string]interface{}{
+		"trace_id":    sc.TraceID().String(),
+		"span_id":     sc.SpanID().String(),
+		"trace_flags": sc.TraceFlags().String(),
+	}
+}
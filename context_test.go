@@ -0,0 +1,50 @@
+package logger
+
+import (
+	"context"
+	"testing"
+
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+)
+
+func TestContextWithFieldsRoundTrips(t *testing.T) {
+	var ctx = ContextWithFields(context.Background(), map[string]interface{}{"tenant": "acme"})
+	ctx = ContextWithFields(ctx, map[string]interface{}{"user_id": "u1"})
+
+	var got = FieldsFromContext(ctx)
+	if got["tenant"] != "acme" || got["user_id"] != "u1" {
+		t.Fatalf("expected both stashed fields to survive, got %#v", got)
+	}
+}
+
+func TestFieldsFromContextWithNoFields(t *testing.T) {
+	if got := FieldsFromContext(context.Background()); got != nil {
+		t.Fatalf("expected nil for a context with no stashed fields, got %#v", got)
+	}
+}
+
+func TestTraceFieldsWithRealSpan(t *testing.T) {
+	var tp = sdktrace.NewTracerProvider()
+	defer tp.Shutdown(context.Background())
+
+	var ctx, span = tp.Tracer("test").Start(context.Background(), "op")
+	defer span.End()
+
+	var fields = traceFields(ctx)
+	var sc = span.SpanContext()
+	if fields["trace_id"] != sc.TraceID().String() {
+		t.Fatalf("expected trace_id %q, got %#v", sc.TraceID().String(), fields["trace_id"])
+	}
+	if fields["span_id"] != sc.SpanID().String() {
+		t.Fatalf("expected span_id %q, got %#v", sc.SpanID().String(), fields["span_id"])
+	}
+	if fields["trace_flags"] != sc.TraceFlags().String() {
+		t.Fatalf("expected trace_flags %q, got %#v", sc.TraceFlags().String(), fields["trace_flags"])
+	}
+}
+
+func TestTraceFieldsWithNoSpan(t *testing.T) {
+	if fields := traceFields(context.Background()); fields != nil {
+		t.Fatalf("expected nil trace fields for a context with no span, got %#v", fields)
+	}
+}
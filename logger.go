@@ -2,10 +2,11 @@
 package logger
 
 import (
+	"context"
 	"encoding/json"
-	"io"
 	"os"
 	"strings"
+	"sync"
 	"time"
 )
 
@@ -36,15 +37,60 @@ type formatedJSON struct {
 	Data      map[string]interface{} `json:"data"`
 }
 
+// Hook is called for every logged entry that passes the minimum level
+// filter and is not dropped by the Logger's Sampler, after the entry has
+// been encoded. It receives the level and the
+// data map that was logged, letting callers fan out to metrics/alerting
+// systems without wrapping the encoder.
+type Hook func(Level, map[string]interface{})
+
 // Logger contains all the variables needed by the logger:
+//    - mu: protects concurrent access to the encoder, since several
+//          goroutines may hold copies of the same *Logger.
 //    - level: Minimum level which will be logged, lower levels are simply
 //             discarded.
 //    - logger: JSON encoder which will be used to log.
-//    - json: base struct which will be logged (more infos above).
+//    - process: Name of the current program using the logger.
+//    - fields: Fields carried by this logger instance, merged into every
+//              entry it logs. Derived loggers (WithFields, WithError,
+//              WithContext) get their own copy so mutating one never
+//              affects another.
+//    - hooks: Functions invoked for every logged entry.
+//    - ctx: Context carried by this logger instance, set through
+//           WithContext. Its request-scoped data is merged into fields
+//           by WithContext itself; log does not read ctx.
+//    - sampler: Optional Sampler consulted before encoding an entry, set
+//               through WithSampler.
+//    - dropped: Per-level count of entries the sampler dropped since the
+//               last periodic report, shared across loggers derived from
+//               one another (see sampler.go).
+//    - lastDropReport: UnixNano of the last periodic drop report.
+//    - clock: Source of the current time, set through WithClock. Defaults
+//             to the real wall clock; tests can inject a fake one.
+//    - timeFormat: time.Time layout used for the "timestamp" field, set
+//                  through WithTimeFormat. Defaults to time.RFC3339Nano.
+//    - callerSkip: Number of stack frames to skip when resolving the
+//                  caller's file/line, set through WithCaller. Negative
+//                  means caller info is disabled (the default).
+//    - captureStack: Whether to attach a trimmed stack trace to ERROR and
+//                    FATAL entries, set through WithStack.
 type Logger struct {
-	level  Level
-	logger *json.Encoder
-	json   formatedJSON
+	mu      *sync.Mutex
+	level   Level
+	logger  *json.Encoder
+	process string
+	fields  map[string]interface{}
+	hooks   []Hook
+	ctx     context.Context
+
+	sampler        Sampler
+	dropped        *[6]uint64
+	lastDropReport *int64
+
+	clock        Clock
+	timeFormat   string
+	callerSkip   int
+	captureStack bool
 }
 
 // levelToString returns a map allowing the conversion of a Level into a string
@@ -76,66 +122,240 @@ func stringToLevel() map[string]Level {
 	return stringlvls
 }
 
-// stringToWriter returns an io.Writer based on a string.
-// Add a case here if you want to add a new type of writer to the logger.
-func stringToWriter(writer string) io.Writer {
-	switch writer {
-	case "stdout":
-		return os.Stdout
-	default:
-		return os.Stdout
-		// var f, err = os.OpenFile("/var/log/"+os.Args[0]+".log", os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0644)
-		// if err != nil {
-		// 	panic(err)
-		// }
-		// return f
-	}
-}
-
 // New creates, initializes and returns a new Logger.
 // Parameters of the function are:
 //    - level: string representing the minimum logging level.
-//    - writer: string representing the selected writer.
+//    - writer: string representing the selected writer(s), as a
+//              comma-separated list of DSNs, e.g.
+//              "stdout,file:///var/log/app.log?rotate=100MB&keep=7". Built-in
+//              schemes are stdout, stderr, file, syslog, tcp and udp; more
+//              can be added with RegisterWriter.
 //    - prettyprint: string representing a bool, activating the pretty print or
 //                   not (`true` or `false`).
 // Note that the function protoptype has been made so you can use it with
 // environment variables, like that:
-//    `var lg = logger.New(os.Getenv("LOG_LEVEL"), os.Getenv("LOG_WRITER"), os.Getenv("LOG_PRETTY"))`
+//    `var lg, err = logger.New(os.Getenv("LOG_LEVEL"), os.Getenv("LOG_WRITER"), os.Getenv("LOG_PRETTY"))`
 // LOG_LEVEL, LOG_WRITER and LOG_PRETTY can be set just before the program, or
 // exported in the environment:
 //    `LOG_LEVEL=DEBUG LOG_WRITER=stdout, LOG_PRETTY=true ./my_program`
 // OR
 //    `export LOG_LEVEL=WARN`
 //    `./my_program` (which will be run with a WARN log level)
-func New(level string, writer string, prettyprint string) Logger {
-	var lg Logger
-	lg.level = stringToLevel()[level]
-	lg.logger = json.NewEncoder(stringToWriter(writer))
+func New(level string, writer string, prettyprint string) (*Logger, error) {
+	var w, err = buildWriter(writer)
+	if err != nil {
+		return nil, err
+	}
+
+	var lg = &Logger{
+		mu:             &sync.Mutex{},
+		level:          stringToLevel()[level],
+		dropped:        &[6]uint64{},
+		lastDropReport: new(int64),
+		clock:          realClock{},
+		timeFormat:     time.RFC3339Nano,
+		callerSkip:     -1,
+	}
+	lg.logger = json.NewEncoder(w)
 	if prettyprint == "true" {
 		lg.logger.SetIndent("", "  ")
 	}
 
 	var process = strings.Split(os.Args[0], "/")
-	lg.json.Process = process[len(process)-1]
+	lg.process = process[len(process)-1]
 
-	return lg
+	return lg, nil
+}
+
+// clone returns a shallow copy of lg whose fields map is a deep copy of the
+// original. This is what the fluent With* methods build upon so a derived
+// logger never aliases (and therefore never mutates) the fields of the
+// logger it was derived from.
+func (lg *Logger) clone() *Logger {
+	var fields = make(map[string]interface{}, len(lg.fields))
+	for k, v := range lg.fields {
+		fields[k] = v
+	}
+
+	var hooks = make([]Hook, len(lg.hooks))
+	copy(hooks, lg.hooks)
+
+	return &Logger{
+		mu:      lg.mu,
+		level:   lg.level,
+		logger:  lg.logger,
+		process: lg.process,
+		fields:  fields,
+		hooks:   hooks,
+		ctx:     lg.ctx,
+
+		sampler:        lg.sampler,
+		dropped:        lg.dropped,
+		lastDropReport: lg.lastDropReport,
+
+		clock:        lg.clock,
+		timeFormat:   lg.timeFormat,
+		callerSkip:   lg.callerSkip,
+		captureStack: lg.captureStack,
+	}
+}
+
+// WithFields returns a derived Logger carrying a copy of fields merged on
+// top of lg's own fields. lg itself is left untouched.
+func (lg *Logger) WithFields(fields map[string]interface{}) *Logger {
+	var derived = lg.clone()
+	for k, v := range fields {
+		derived.fields[k] = v
+	}
+	return derived
+}
+
+// WithError returns a derived Logger with an "error" field set to err's
+// message. A nil err is a no-op: lg is cloned but no "error" field is set.
+func (lg *Logger) WithError(err error) *Logger {
+	if err == nil {
+		return lg.clone()
+	}
+	return lg.WithFields(map[string]interface{}{"error": err.Error()})
+}
+
+// WithContext returns a derived Logger carrying ctx, with two kinds of
+// context-scoped data merged into its fields:
+//   - any fields stashed in ctx through ContextWithFields (e.g. by
+//     HTTPMiddleware), and
+//   - trace_id, span_id and trace_flags, when ctx carries a valid
+//     OpenTelemetry span (see traceFields in context.go).
+func (lg *Logger) WithContext(ctx context.Context) *Logger {
+	var derived = lg.clone()
+	derived.ctx = ctx
+	for k, v := range FieldsFromContext(ctx) {
+		derived.fields[k] = v
+	}
+	for k, v := range traceFields(ctx) {
+		derived.fields[k] = v
+	}
+	return derived
+}
+
+// AddHook registers a hook on lg. Hooks are called, in registration order,
+// for every entry logged by lg or by loggers derived from it after the
+// call.
+func (lg *Logger) AddHook(hook Hook) {
+	lg.mu.Lock()
+	defer lg.mu.Unlock()
+	lg.hooks = append(lg.hooks, hook)
 }
 
 // Log writes and outputs a given data *v* to a given Level *level*.
-func (lg Logger) Log(level Level, v ...interface{}) {
-	if level >= lg.level {
-		lg.json.Timestamp = time.Now().Format("2006-01-02 15:04:05")
-		lg.json.Level = levelToString()[level]
-		lg.json.Data = make(map[string]interface{})
-		var n = len(v)
-		for i := 0; i < n; i = i + 2 {
-			if str, ok := v[i].(string); ok {
-				lg.json.Data[str] = v[i+1]
-			} else {
-				panic("json key must be a string")
-			}
+func (lg *Logger) Log(level Level, v ...interface{}) {
+	lg.log(level, 1, v...)
+}
+
+// log is Log's implementation, plus extraSkip: the number of stack frames
+// between log itself and the user's call site, so WithCaller reports the
+// right location regardless of how log was reached. Log and the per-level
+// helpers (Debug, Info, ...) are each a single wrapper frame around log, so
+// both call it with extraSkip 1.
+func (lg *Logger) log(level Level, extraSkip int, v ...interface{}) {
+	if level < lg.level {
+		return
+	}
+
+	var data = make(map[string]interface{}, len(lg.fields)+len(v)/2)
+	for k, val := range lg.fields {
+		data[k] = val
+	}
+
+	var n = len(v)
+	for i := 0; i < n; i = i + 2 {
+		if str, ok := v[i].(string); ok {
+			data[str] = v[i+1]
+		} else {
+			panic("json key must be a string")
+		}
+	}
+
+	if lg.sampler != nil && !lg.sampler.Check(level, samplingKey(data)) {
+		lg.recordDropped(level)
+		return
+	}
+
+	if lg.callerSkip >= 0 {
+		if file, line, ok := callerInfo(lg.callerSkip + extraSkip); ok {
+			data["file"] = file
+			data["line"] = line
 		}
+	}
+	if lg.captureStack && (level == ERROR || level == FATAL) {
+		data["stack"] = trimmedStack()
+	}
+
+	lg.encode(level, data)
 
-		lg.logger.Encode(lg.json)
+	lg.mu.Lock()
+	var hooks = make([]Hook, len(lg.hooks))
+	copy(hooks, lg.hooks)
+	lg.mu.Unlock()
+
+	for _, hook := range hooks {
+		hook(level, data)
+	}
+
+	if lg.sampler != nil {
+		lg.maybeReportDropped()
 	}
 }
+
+// encode writes data to lg's underlying encoder as a single JSON entry at
+// level, bypassing the sampler. It is used both by Log and to emit the
+// synthetic drop-count reports a sampler produces, which must never be
+// sampled out themselves.
+func (lg *Logger) encode(level Level, data map[string]interface{}) {
+	var entry = formatedJSON{
+		Process:   lg.process,
+		Timestamp: lg.clock.Now().Format(lg.timeFormat),
+		Level:     levelToString()[level],
+		Data:      data,
+	}
+
+	lg.mu.Lock()
+	lg.logger.Encode(entry)
+	lg.mu.Unlock()
+}
+
+// kvFromMsg turns a message and a variadic list of key/value pairs into the
+// arguments Log expects, always carrying the message under the "message"
+// key.
+func kvFromMsg(msg string, kv ...interface{}) []interface{} {
+	var v = make([]interface{}, 0, len(kv)+2)
+	v = append(v, "message", msg)
+	v = append(v, kv...)
+	return v
+}
+
+// Debug logs msg and the given key/value pairs at the DEBUG level.
+func (lg *Logger) Debug(msg string, kv ...interface{}) {
+	lg.log(DEBUG, 1, kvFromMsg(msg, kv...)...)
+}
+
+// Info logs msg and the given key/value pairs at the INFO level.
+func (lg *Logger) Info(msg string, kv ...interface{}) {
+	lg.log(INFO, 1, kvFromMsg(msg, kv...)...)
+}
+
+// Warn logs msg and the given key/value pairs at the WARN level.
+func (lg *Logger) Warn(msg string, kv ...interface{}) {
+	lg.log(WARN, 1, kvFromMsg(msg, kv...)...)
+}
+
+// Error logs msg and the given key/value pairs at the ERROR level.
+func (lg *Logger) Error(msg string, kv ...interface{}) {
+	lg.log(ERROR, 1, kvFromMsg(msg, kv...)...)
+}
+
+// Fatal logs msg and the given key/value pairs at the FATAL level, then
+// flushes and terminates the program with exit code 1.
+func (lg *Logger) Fatal(msg string, kv ...interface{}) {
+	lg.log(FATAL, 1, kvFromMsg(msg, kv...)...)
+	os.Exit(1)
+}
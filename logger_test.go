@@ -0,0 +1,49 @@
+package logger
+
+import (
+	"path/filepath"
+	"sync"
+	"testing"
+)
+
+func newTestLogger(t testing.TB) *Logger {
+	t.Helper()
+	var path = filepath.Join(t.TempDir(), "test.log")
+	lg, err := New("DEBUG", "file://"+path, "false")
+	if err != nil {
+		t.Fatalf("New: %v", err)
+	}
+	return lg
+}
+
+func TestAddHookConcurrentWithLog(t *testing.T) {
+	var lg = newTestLogger(t)
+
+	var wg sync.WaitGroup
+	wg.Add(2)
+
+	go func() {
+		defer wg.Done()
+		for i := 0; i < 100; i++ {
+			lg.Log(INFO, "i", i)
+		}
+	}()
+
+	go func() {
+		defer wg.Done()
+		for i := 0; i < 100; i++ {
+			lg.AddHook(func(Level, map[string]interface{}) {})
+		}
+	}()
+
+	wg.Wait()
+}
+
+func TestWithErrorNil(t *testing.T) {
+	var lg = newTestLogger(t)
+
+	var derived = lg.WithError(nil)
+	if _, ok := derived.fields["error"]; ok {
+		t.Fatalf("expected no error field to be set for a nil error")
+	}
+}
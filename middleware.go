@@ -0,0 +1,98 @@
+package logger
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"net/http"
+	"time"
+)
+
+// requestIDHeader is the header HTTPMiddleware reads an inbound request ID
+// from, and sets on every response (to the inbound ID, or to a generated one
+// when the request carried none).
+const requestIDHeader = "X-Request-ID"
+
+// loggerContextKey is the context.Context key HTTPMiddleware stashes the
+// per-request *Logger under.
+const loggerContextKey contextKey = 1
+
+// LoggerFromContext returns the per-request *Logger HTTPMiddleware stashed
+// in ctx, or nil if ctx carries none.
+func LoggerFromContext(ctx context.Context) *Logger {
+	lg, _ := ctx.Value(loggerContextKey).(*Logger)
+	return lg
+}
+
+// generateRequestID returns a random 16-byte hex-encoded request ID.
+func generateRequestID() string {
+	var b [16]byte
+	rand.Read(b[:])
+	return hex.EncodeToString(b[:])
+}
+
+// statusRecorder wraps an http.ResponseWriter to capture the status code
+// and byte count HTTPMiddleware logs once the request completes.
+type statusRecorder struct {
+	http.ResponseWriter
+	status int
+	bytes  int
+}
+
+func (r *statusRecorder) WriteHeader(status int) {
+	r.status = status
+	r.ResponseWriter.WriteHeader(status)
+}
+
+func (r *statusRecorder) Write(p []byte) (int, error) {
+	if r.status == 0 {
+		r.status = http.StatusOK
+	}
+	var n, err = r.ResponseWriter.Write(p)
+	r.bytes += n
+	return n, err
+}
+
+// HTTPMiddleware returns net/http middleware that, for every request:
+//   - propagates the X-Request-ID header, generating one if the client
+//     didn't send it,
+//   - stashes a *Logger carrying that request_id (retrievable with
+//     LoggerFromContext) in the request's context, and
+//   - logs the request's start and completion, the latter with status,
+//     duration and response size.
+func HTTPMiddleware(lg *Logger) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			var requestID = r.Header.Get(requestIDHeader)
+			if requestID == "" {
+				requestID = generateRequestID()
+			}
+			w.Header().Set(requestIDHeader, requestID)
+
+			var reqLogger = lg.WithFields(map[string]interface{}{"request_id": requestID})
+
+			var ctx = ContextWithFields(r.Context(), map[string]interface{}{"request_id": requestID})
+			ctx = context.WithValue(ctx, loggerContextKey, reqLogger)
+			r = r.WithContext(ctx)
+
+			reqLogger.Info("request started", "method", r.Method, "path", r.URL.Path)
+
+			var rec = &statusRecorder{ResponseWriter: w}
+			var start = time.Now()
+
+			next.ServeHTTP(rec, r)
+
+			if rec.status == 0 {
+				rec.status = http.StatusOK
+			}
+
+			reqLogger.Info("request completed",
+				"method", r.Method,
+				"path", r.URL.Path,
+				"status", rec.status,
+				"duration_ms", time.Since(start).Milliseconds(),
+				"bytes", rec.bytes,
+			)
+		})
+	}
+}
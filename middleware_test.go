@@ -0,0 +1,69 @@
+package logger
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestHTTPMiddlewareGeneratesAndPropagatesRequestID(t *testing.T) {
+	var lg = newTestLogger(t)
+
+	var fromCtx *Logger
+	var handler = HTTPMiddleware(lg)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		fromCtx = LoggerFromContext(r.Context())
+		w.WriteHeader(http.StatusTeapot)
+	}))
+
+	var rec = httptest.NewRecorder()
+	var req = httptest.NewRequest(http.MethodGet, "/widgets", nil)
+	handler.ServeHTTP(rec, req)
+
+	var requestID = rec.Header().Get(requestIDHeader)
+	if requestID == "" {
+		t.Fatalf("expected %s response header to be set", requestIDHeader)
+	}
+
+	if fromCtx == nil {
+		t.Fatalf("expected LoggerFromContext to return a logger")
+	}
+	if fromCtx.fields["request_id"] != requestID {
+		t.Fatalf("expected the context logger to carry request_id %q, got %#v", requestID, fromCtx.fields["request_id"])
+	}
+}
+
+func TestHTTPMiddlewarePropagatesInboundRequestID(t *testing.T) {
+	var lg = newTestLogger(t)
+
+	var handler = HTTPMiddleware(lg)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+
+	var rec = httptest.NewRecorder()
+	var req = httptest.NewRequest(http.MethodGet, "/widgets", nil)
+	req.Header.Set(requestIDHeader, "inbound-id")
+	handler.ServeHTTP(rec, req)
+
+	if got := rec.Header().Get(requestIDHeader); got != "inbound-id" {
+		t.Fatalf("expected the inbound request ID to be echoed back, got %q", got)
+	}
+}
+
+func TestHTTPMiddlewareDefaultsStatusToOKWhenHandlerNeverWrites(t *testing.T) {
+	var lg = newTestLogger(t)
+
+	var captured map[string]interface{}
+	lg.AddHook(func(_ Level, data map[string]interface{}) {
+		if data["message"] == "request completed" {
+			captured = data
+		}
+	})
+
+	var handler = HTTPMiddleware(lg)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+
+	var rec = httptest.NewRecorder()
+	var req = httptest.NewRequest(http.MethodGet, "/widgets", nil)
+	handler.ServeHTTP(rec, req)
+
+	if captured["status"] != http.StatusOK {
+		t.Fatalf("expected the logged status to default to 200, got %#v", captured["status"])
+	}
+}
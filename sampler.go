@@ -0,0 +1,194 @@
+package logger
+
+import (
+	"sort"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// Sampler decides whether a log entry at the given level and sampling key
+// should be kept or dropped. It is consulted by Log before the entry is
+// encoded, so a Sampler that returns false avoids paying the encoding cost
+// entirely.
+type Sampler interface {
+	Check(level Level, key string) bool
+}
+
+// WithSampler returns a derived Logger that consults sampler before
+// encoding each entry. Passing a nil sampler disables sampling on the
+// derived logger.
+func (lg *Logger) WithSampler(sampler Sampler) *Logger {
+	var derived = lg.clone()
+	derived.sampler = sampler
+	return derived
+}
+
+// samplingKey derives the key a Sampler sees for a given log entry: the
+// "message" field when present (grouping repeated log call-sites
+// together), otherwise the sorted, joined set of data keys.
+func samplingKey(data map[string]interface{}) string {
+	if msg, ok := data["message"].(string); ok {
+		return msg
+	}
+
+	var keys = make([]string, 0, len(data))
+	for k := range data {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return strings.Join(keys, ",")
+}
+
+// dropReportInterval is how often a Logger with a sampler reports the
+// number of entries it dropped per level since the last report.
+const dropReportInterval = 10 * time.Second
+
+// recordDropped increments lg's dropped-entry counter for level and, once
+// dropReportInterval has elapsed, emits a synthetic
+// {"dropped": N, "level": "..."} INFO record per level that dropped at
+// least one entry, bypassing the sampler so the report itself is never
+// dropped.
+func (lg *Logger) recordDropped(level Level) {
+	atomic.AddUint64(&lg.dropped[level], 1)
+	lg.maybeReportDropped()
+}
+
+// maybeReportDropped flushes accumulated drop counts to synthetic INFO
+// records if dropReportInterval has elapsed since the last flush.
+func (lg *Logger) maybeReportDropped() {
+	var now = time.Now().UnixNano()
+	var last = atomic.LoadInt64(lg.lastDropReport)
+	if now-last < int64(dropReportInterval) {
+		return
+	}
+	if !atomic.CompareAndSwapInt64(lg.lastDropReport, last, now) {
+		return
+	}
+
+	for level := DEBUG; level <= FATAL; level++ {
+		var n = atomic.SwapUint64(&lg.dropped[level], 0)
+		if n == 0 {
+			continue
+		}
+		lg.encode(INFO, map[string]interface{}{
+			"dropped": n,
+			"level":   levelToString()[level],
+		})
+	}
+}
+
+// tokenBucket is a single per-level token bucket used by TokenBucketSampler.
+type tokenBucket struct {
+	mu     sync.Mutex
+	tokens float64
+	last   time.Time
+}
+
+// take attempts to consume one token, refilling the bucket for the time
+// elapsed since the last call at refillPerSecond tokens/second, capped at
+// burst tokens.
+func (b *tokenBucket) take(burst float64, refillPerSecond float64) bool {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	var now = time.Now()
+	if b.last.IsZero() {
+		b.tokens = burst
+	} else {
+		var elapsed = now.Sub(b.last).Seconds()
+		b.tokens += elapsed * refillPerSecond
+		if b.tokens > burst {
+			b.tokens = burst
+		}
+	}
+	b.last = now
+
+	if b.tokens < 1 {
+		return false
+	}
+	b.tokens--
+	return true
+}
+
+// TokenBucketSampler is a Sampler that rate-limits each Level independently
+// through a token bucket: up to burst entries may be logged in a row, after
+// which entries are allowed through at refillPerSecond per second.
+type TokenBucketSampler struct {
+	burst           float64
+	refillPerSecond float64
+
+	mu      sync.Mutex
+	buckets map[Level]*tokenBucket
+}
+
+// NewTokenBucketSampler returns a TokenBucketSampler allowing bursts of up
+// to burst entries per level, refilling at refillPerSecond entries per
+// second thereafter.
+func NewTokenBucketSampler(burst int, refillPerSecond float64) *TokenBucketSampler {
+	return &TokenBucketSampler{
+		burst:           float64(burst),
+		refillPerSecond: refillPerSecond,
+		buckets:         make(map[Level]*tokenBucket),
+	}
+}
+
+// Check implements Sampler. key is ignored: this sampler only limits by
+// level.
+func (s *TokenBucketSampler) Check(level Level, key string) bool {
+	s.mu.Lock()
+	var b, ok = s.buckets[level]
+	if !ok {
+		b = &tokenBucket{}
+		s.buckets[level] = b
+	}
+	s.mu.Unlock()
+
+	return b.take(s.burst, s.refillPerSecond)
+}
+
+// basicCounter is the per-key, per-second counter used by BasicSampler. A
+// new second resets count to start the "first N" window over.
+type basicCounter struct {
+	second int64
+	count  uint64
+}
+
+// BasicSampler is a Sampler implementing the common "log the first N
+// occurrences, then every Mth one" policy, bucketed per second and keyed by
+// the Sampler.Check key (see samplingKey).
+type BasicSampler struct {
+	first      int
+	thereafter int
+	counters   sync.Map // string -> *basicCounter
+}
+
+// NewBasicSampler returns a BasicSampler that lets the first `first`
+// occurrences per second through, then every `thereafter`th occurrence
+// after those. A thereafter of zero or less drops everything past the first.
+func NewBasicSampler(first int, thereafter int) *BasicSampler {
+	return &BasicSampler{first: first, thereafter: thereafter}
+}
+
+// Check implements Sampler.
+func (s *BasicSampler) Check(level Level, key string) bool {
+	var bucketKey = strings.Join([]string{levelToString()[level], key}, "\x00")
+	var now = time.Now().Unix()
+
+	var value, _ = s.counters.LoadOrStore(bucketKey, &basicCounter{second: now})
+	var c = value.(*basicCounter)
+
+	if atomic.SwapInt64(&c.second, now) != now {
+		atomic.StoreUint64(&c.count, 0)
+	}
+
+	var n = atomic.AddUint64(&c.count, 1)
+	if int(n) <= s.first {
+		return true
+	}
+	if s.thereafter <= 0 {
+		return false
+	}
+	return (int(n)-s.first)%s.thereafter == 0
+}
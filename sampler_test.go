@@ -0,0 +1,90 @@
+package logger
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestBasicSamplerFirstNThenEveryMth(t *testing.T) {
+	var s = NewBasicSampler(2, 3)
+
+	var got []bool
+	for i := 0; i < 8; i++ {
+		got = append(got, s.Check(INFO, "k"))
+	}
+
+	var want = []bool{true, true, false, false, true, false, false, true}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Fatalf("check %d: got %v, want %v (full: %v)", i, got[i], want[i], got)
+		}
+	}
+}
+
+func TestTokenBucketSamplerLimitsBurst(t *testing.T) {
+	var s = NewTokenBucketSampler(2, 0)
+
+	if !s.Check(INFO, "") {
+		t.Fatalf("expected first check within burst to pass")
+	}
+	if !s.Check(INFO, "") {
+		t.Fatalf("expected second check within burst to pass")
+	}
+	if s.Check(INFO, "") {
+		t.Fatalf("expected third check to be dropped once burst is exhausted with no refill")
+	}
+}
+
+func TestLogSampledOutIsDropped(t *testing.T) {
+	var path = filepath.Join(t.TempDir(), "test.log")
+	lg, err := New("DEBUG", "file://"+path, "false")
+	if err != nil {
+		t.Fatalf("New: %v", err)
+	}
+	lg = lg.WithSampler(NewBasicSampler(0, 0))
+
+	var called bool
+	lg.AddHook(func(Level, map[string]interface{}) { called = true })
+
+	lg.Info("never logged")
+
+	if called {
+		t.Fatalf("expected the sampler to drop every entry and hooks to never run")
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("reading log output: %v", err)
+	}
+	if strings.Contains(string(data), "never logged") {
+		t.Fatalf("expected the sampled-out entry to never reach the encoder, got %q", data)
+	}
+	if !strings.Contains(string(data), `"dropped":1`) {
+		t.Fatalf("expected a synthetic dropped-count report in the log output, got %q", data)
+	}
+}
+
+// BenchmarkLogSampledOut measures the cost of a Log call that a Sampler
+// drops before encoding: it should be far cheaper than BenchmarkLogEncoded
+// since it never touches the JSON encoder.
+func BenchmarkLogSampledOut(b *testing.B) {
+	var lg = newTestLogger(b).WithSampler(NewBasicSampler(0, 0))
+
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		lg.Info("benchmark", "i", i)
+	}
+}
+
+// BenchmarkLogEncoded measures the cost of a Log call that is not sampled
+// out, for comparison against BenchmarkLogSampledOut.
+func BenchmarkLogEncoded(b *testing.B) {
+	var lg = newTestLogger(b)
+
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		lg.Info("benchmark", "i", i)
+	}
+}
@@ -0,0 +1,149 @@
+//go:build go1.21
+
+package logger
+
+import (
+	"context"
+	"log/slog"
+)
+
+// slogFatalLevel is the conventional level value some slog setups use for a
+// "fatal" record (e.g. slog.LevelError+4). There is no standard slog.Level
+// constant for it, so it is defined here to preserve the
+// negative/positive offset convention slog.Level uses around
+// slog.LevelInfo (0).
+const slogFatalLevel = slog.LevelError + 4
+
+// levelFromSlog maps a slog.Level to the package's Level, preserving slog's
+// offset convention: any level at or above a named threshold (Debug=-4,
+// Info=0, Warn=4, Error=8) maps to that Level, so custom offsets like
+// slog.LevelInfo+2 still land on INFO.
+func levelFromSlog(level slog.Level) Level {
+	switch {
+	case level >= slogFatalLevel:
+		return FATAL
+	case level >= slog.LevelError:
+		return ERROR
+	case level >= slog.LevelWarn:
+		return WARN
+	case level >= slog.LevelInfo:
+		return INFO
+	default:
+		return DEBUG
+	}
+}
+
+// SlogHandler is a slog.Handler backed by a *Logger, so programs can use
+// log/slog's API while keeping this package's JSON output (process,
+// timestamp, level, client, data).
+type SlogHandler struct {
+	logger *Logger
+	groups []string
+	attrs  map[string]interface{}
+}
+
+// NewSlogHandler returns a SlogHandler that logs through lg.
+func NewSlogHandler(lg *Logger) *SlogHandler {
+	return &SlogHandler{logger: lg}
+}
+
+// Enabled implements slog.Handler, honoring lg's minimum level.
+func (h *SlogHandler) Enabled(_ context.Context, level slog.Level) bool {
+	return levelFromSlog(level) >= h.logger.level
+}
+
+// Handle implements slog.Handler.
+func (h *SlogHandler) Handle(ctx context.Context, r slog.Record) error {
+	var attrs = make(map[string]interface{}, r.NumAttrs())
+	r.Attrs(func(a slog.Attr) bool {
+		attrs[a.Key] = a.Value.Any()
+		return true
+	})
+
+	var merged = make(map[string]interface{}, len(h.attrs))
+	deepMerge(merged, h.attrs)
+	deepMerge(merged, nestUnderGroups(h.groups, attrs))
+
+	var kv = make([]interface{}, 0, len(merged)*2+2)
+	kv = append(kv, "message", r.Message)
+	for k, v := range merged {
+		kv = append(kv, k, v)
+	}
+
+	h.logger.WithContext(ctx).Log(levelFromSlog(r.Level), kv...)
+	return nil
+}
+
+// WithAttrs implements slog.Handler, nesting attrs under the handler's
+// current groups, just like a record's own attributes, and deep-merging
+// them with any attrs already carried by h so that attrs added at
+// different group depths under the same top-level group name don't
+// clobber each other.
+func (h *SlogHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	var added = make(map[string]interface{}, len(attrs))
+	for _, a := range attrs {
+		added[a.Key] = a.Value.Any()
+	}
+
+	var merged = make(map[string]interface{}, len(h.attrs))
+	deepMerge(merged, h.attrs)
+	deepMerge(merged, nestUnderGroups(h.groups, added))
+
+	return &SlogHandler{logger: h.logger, groups: h.groups, attrs: merged}
+}
+
+// WithGroup implements slog.Handler: every attribute added after this call
+// (through WithAttrs or a record's own attrs) is nested under name in the
+// logged Data map.
+func (h *SlogHandler) WithGroup(name string) slog.Handler {
+	var groups = make([]string, len(h.groups)+1)
+	copy(groups, h.groups)
+	groups[len(groups)-1] = name
+	return &SlogHandler{logger: h.logger, groups: groups, attrs: h.attrs}
+}
+
+// nestUnderGroups wraps attrs under nested maps named after groups, so
+// WithGroup("a").WithGroup("b") attrs end up at data["a"]["b"][key].
+func nestUnderGroups(groups []string, attrs map[string]interface{}) map[string]interface{} {
+	if len(groups) == 0 {
+		return attrs
+	}
+	return map[string]interface{}{groups[0]: nestUnderGroups(groups[1:], attrs)}
+}
+
+// deepMerge merges src into dst in place. Where both src and dst have a
+// nested map[string]interface{} under the same key, it merges them
+// key-by-key instead of one replacing the other, so attrs attached under
+// the same top-level group at different times or depths accumulate
+// instead of clobbering each other. Nested maps copied from src are
+// deep-copied so dst never ends up aliasing (and later mutating) a map
+// still referenced by src.
+func deepMerge(dst, src map[string]interface{}) {
+	for k, v := range src {
+		srcMap, srcIsMap := v.(map[string]interface{})
+		if !srcIsMap {
+			dst[k] = v
+			continue
+		}
+
+		if dstMap, ok := dst[k].(map[string]interface{}); ok {
+			deepMerge(dstMap, srcMap)
+			continue
+		}
+		dst[k] = deepCopyMap(srcMap)
+	}
+}
+
+// deepCopyMap returns a deep copy of m, recursing into nested
+// map[string]interface{} values.
+func deepCopyMap(m map[string]interface{}) map[string]interface{} {
+	var out = make(map[string]interface{}, len(m))
+	for k, v := range m {
+		if nested, ok := v.(map[string]interface{}); ok {
+			out[k] = deepCopyMap(nested)
+		} else {
+			out[k] = v
+		}
+	}
+	return out
+}
@@ -0,0 +1,41 @@
+//go:build go1.21
+
+package logger
+
+import (
+	"context"
+	"log/slog"
+	"testing"
+)
+
+func TestSlogHandlerMergesAttrsAcrossGroupDepths(t *testing.T) {
+	var lg = newTestLogger(t)
+	var h = NewSlogHandler(lg).WithGroup("a").WithAttrs([]slog.Attr{slog.Int("x", 1)}).WithGroup("b")
+
+	var captured map[string]interface{}
+	lg.AddHook(func(_ Level, data map[string]interface{}) {
+		captured = data
+	})
+
+	var r = slog.NewRecord(lg.clock.Now(), slog.LevelInfo, "hi", 0)
+	r.AddAttrs(slog.Int("y", 2))
+	if err := h.Handle(context.Background(), r); err != nil {
+		t.Fatalf("Handle: %v", err)
+	}
+
+	var a, ok = captured["a"].(map[string]interface{})
+	if !ok {
+		t.Fatalf("expected nested \"a\" map, got %#v", captured["a"])
+	}
+	if a["x"] != int64(1) {
+		t.Fatalf("expected a.x=1 to survive the deeper group, got %#v", a["x"])
+	}
+
+	var b, ok2 = a["b"].(map[string]interface{})
+	if !ok2 {
+		t.Fatalf("expected nested \"a.b\" map, got %#v", a["b"])
+	}
+	if b["y"] != int64(2) {
+		t.Fatalf("expected a.b.y=2, got %#v", b["y"])
+	}
+}
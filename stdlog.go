@@ -0,0 +1,26 @@
+package logger
+
+import (
+	"log"
+	"strings"
+)
+
+// stdWriter adapts a *Logger to io.Writer so it can back a *log.Logger: each
+// Write is logged as a single INFO entry, with the trailing newline
+// *log.Logger always appends trimmed off.
+type stdWriter struct {
+	lg *Logger
+}
+
+func (w stdWriter) Write(p []byte) (int, error) {
+	w.lg.Log(INFO, "message", strings.TrimRight(string(p), "\n"))
+	return len(p), nil
+}
+
+// StdLogger returns a *log.Logger backed by lg, so third-party code
+// expecting the standard library's logger (e.g. net/http.Server.ErrorLog)
+// can be routed through this package. Every line written to it is logged
+// through lg.Log at the INFO level.
+func (lg *Logger) StdLogger() *log.Logger {
+	return log.New(stdWriter{lg: lg}, "", 0)
+}
@@ -0,0 +1,280 @@
+package logger
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"net"
+	"net/url"
+	"os"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// WriterFactory builds an io.Writer from a DSN, e.g.
+// "file:///var/log/app.log?rotate=100MB&keep=7". The scheme (the part
+// before "://", or the whole string when there is no "://") is used to pick
+// which registered factory handles the DSN.
+type WriterFactory func(dsn string) (io.Writer, error)
+
+// writerFactories holds the registered WriterFactory functions, keyed by
+// scheme name.
+var writerFactories = map[string]WriterFactory{}
+
+// writerFactoriesMu protects writerFactories against concurrent
+// registration, which is expected to only happen from package init()s.
+var writerFactoriesMu sync.Mutex
+
+func init() {
+	RegisterWriter("stdout", func(string) (io.Writer, error) { return os.Stdout, nil })
+	RegisterWriter("stderr", func(string) (io.Writer, error) { return os.Stderr, nil })
+	RegisterWriter("file", newFileWriter)
+	RegisterWriter("tcp", newNetWriter("tcp"))
+	RegisterWriter("udp", newNetWriter("udp"))
+}
+
+// RegisterWriter makes a writer factory available under name, so it can be
+// picked by a DSN of the form "name://..." (or, for factories that ignore
+// the DSN entirely, just "name"). Registering under a name that is already
+// taken overwrites the previous factory, which lets callers override the
+// built-in stdout/stderr/file/tcp/udp/syslog writers.
+func RegisterWriter(name string, factory WriterFactory) {
+	writerFactoriesMu.Lock()
+	defer writerFactoriesMu.Unlock()
+	writerFactories[name] = factory
+}
+
+// schemeOf returns the scheme of a DSN, i.e. the part before "://". A DSN
+// with no "://" is its own scheme (this is how the bare "stdout" and
+// "stderr" DSNs are handled).
+func schemeOf(dsn string) string {
+	if i := strings.Index(dsn, "://"); i >= 0 {
+		return dsn[:i]
+	}
+	return dsn
+}
+
+// buildWriter turns a comma-separated list of DSNs into a single io.Writer
+// that fans writes out to all of them via io.MultiWriter. An empty writer
+// string, or one made only of blank entries, falls back to stdout.
+func buildWriter(writer string) (io.Writer, error) {
+	var writers []io.Writer
+
+	for _, dsn := range strings.Split(writer, ",") {
+		dsn = strings.TrimSpace(dsn)
+		if dsn == "" {
+			continue
+		}
+
+		var scheme = schemeOf(dsn)
+		writerFactoriesMu.Lock()
+		var factory, ok = writerFactories[scheme]
+		writerFactoriesMu.Unlock()
+		if !ok {
+			return nil, fmt.Errorf("logger: no writer registered for %q (from dsn %q)", scheme, dsn)
+		}
+
+		w, err := factory(dsn)
+		if err != nil {
+			return nil, fmt.Errorf("logger: building writer %q: %w", scheme, err)
+		}
+		writers = append(writers, w)
+	}
+
+	switch len(writers) {
+	case 0:
+		return os.Stdout, nil
+	case 1:
+		return writers[0], nil
+	default:
+		return io.MultiWriter(writers...), nil
+	}
+}
+
+// parseDSN splits a DSN into its net/url representation, tolerating the
+// bare "stdout"/"stderr" form by returning a URL carrying only the Scheme
+// (set to the whole DSN) for DSNs without "://".
+func parseDSN(dsn string) (*url.URL, error) {
+	if !strings.Contains(dsn, "://") {
+		return &url.URL{Scheme: dsn}, nil
+	}
+	return url.Parse(dsn)
+}
+
+// parseSize parses a human size like "100MB" or "512KB" into a byte count.
+// It understands the B/KB/MB/GB suffixes (powers of 1024) and defaults to
+// bytes when no suffix is given.
+func parseSize(s string) (int64, error) {
+	if s == "" {
+		return 0, nil
+	}
+
+	var upper = strings.ToUpper(strings.TrimSpace(s))
+	var units = []struct {
+		suffix string
+		factor int64
+	}{
+		{"GB", 1024 * 1024 * 1024},
+		{"MB", 1024 * 1024},
+		{"KB", 1024},
+		{"B", 1},
+	}
+
+	for _, u := range units {
+		if strings.HasSuffix(upper, u.suffix) {
+			var n, err = strconv.ParseInt(strings.TrimSuffix(upper, u.suffix), 10, 64)
+			if err != nil {
+				return 0, fmt.Errorf("invalid size %q: %w", s, err)
+			}
+			return n * u.factor, nil
+		}
+	}
+
+	return strconv.ParseInt(upper, 10, 64)
+}
+
+// newFileWriter builds a rotating file writer from a "file://" DSN. The
+// path comes from the DSN's path component, and rotation is controlled by
+// two query parameters:
+//   - rotate: max size before rotating, e.g. "100MB". Zero/absent disables
+//     size-based rotation.
+//   - keep: number of rotated backups to keep (app.log.1, app.log.2, ...).
+//     Older backups beyond this count are removed.
+func newFileWriter(dsn string) (io.Writer, error) {
+	var u, err = parseDSN(dsn)
+	if err != nil {
+		return nil, err
+	}
+
+	var path = u.Path
+	if path == "" {
+		return nil, fmt.Errorf("file writer requires a path, got dsn %q", dsn)
+	}
+
+	var maxBytes int64
+	var keep int
+	if q := u.Query(); true {
+		if maxBytes, err = parseSize(q.Get("rotate")); err != nil {
+			return nil, err
+		}
+		if k := q.Get("keep"); k != "" {
+			if keep, err = strconv.Atoi(k); err != nil {
+				return nil, fmt.Errorf("invalid keep %q: %w", k, err)
+			}
+		}
+	}
+
+	var rf = &rotatingFile{path: path, maxBytes: maxBytes, keep: keep}
+	if err := rf.open(); err != nil {
+		return nil, err
+	}
+	return rf, nil
+}
+
+// rotatingFile is an io.Writer over a file on disk that rotates itself,
+// lumberjack-style, once it grows past maxBytes, keeping up to keep rotated
+// backups named path.1, path.2, ...
+type rotatingFile struct {
+	mu       sync.Mutex
+	path     string
+	maxBytes int64
+	keep     int
+	file     *os.File
+	size     int64
+}
+
+func (rf *rotatingFile) open() error {
+	var f, err = os.OpenFile(rf.path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0644)
+	if err != nil {
+		return err
+	}
+
+	var size int64
+	if info, err := f.Stat(); err == nil {
+		size = info.Size()
+	}
+
+	rf.file = f
+	rf.size = size
+	return nil
+}
+
+func (rf *rotatingFile) Write(p []byte) (int, error) {
+	rf.mu.Lock()
+	defer rf.mu.Unlock()
+
+	if rf.maxBytes > 0 && rf.size > 0 && rf.size+int64(len(p)) > rf.maxBytes {
+		if err := rf.rotate(); err != nil {
+			return 0, err
+		}
+	}
+
+	var n, err = rf.file.Write(p)
+	rf.size += int64(n)
+	return n, err
+}
+
+func (rf *rotatingFile) rotate() error {
+	if err := rf.file.Close(); err != nil {
+		return err
+	}
+
+	if rf.keep > 0 {
+		for i := rf.keep - 1; i >= 1; i-- {
+			var oldName = fmt.Sprintf("%s.%d", rf.path, i)
+			var newName = fmt.Sprintf("%s.%d", rf.path, i+1)
+			os.Rename(oldName, newName)
+		}
+		if err := os.Rename(rf.path, rf.path+".1"); err != nil && !os.IsNotExist(err) {
+			return err
+		}
+	} else if err := os.Truncate(rf.path, 0); err != nil && !os.IsNotExist(err) {
+		// No backups requested: there is nothing to rename the oversized
+		// file to, so just truncate it in place instead of leaving it to
+		// grow forever.
+		return err
+	}
+
+	return rf.open()
+}
+
+// newNetWriter returns a WriterFactory that dials a "tcp://" or "udp://"
+// DSN and returns a line-buffered writer over the connection, suitable for
+// shipping logs to a collector such as Logstash or Fluentd. Each Write is
+// flushed immediately so entries are never stuck in a partially-filled
+// buffer.
+func newNetWriter(network string) WriterFactory {
+	return func(dsn string) (io.Writer, error) {
+		var u, err = parseDSN(dsn)
+		if err != nil {
+			return nil, err
+		}
+
+		conn, err := net.Dial(network, u.Host)
+		if err != nil {
+			return nil, err
+		}
+
+		return &flushingWriter{w: bufio.NewWriter(conn)}, nil
+	}
+}
+
+// flushingWriter wraps a *bufio.Writer and flushes it after every Write, so
+// it behaves like a line-buffered writer without needing to scan for
+// newlines itself (log entries are always written whole).
+type flushingWriter struct {
+	mu sync.Mutex
+	w  *bufio.Writer
+}
+
+func (fw *flushingWriter) Write(p []byte) (int, error) {
+	fw.mu.Lock()
+	defer fw.mu.Unlock()
+
+	var n, err = fw.w.Write(p)
+	if err != nil {
+		return n, err
+	}
+	return n, fw.w.Flush()
+}
@@ -0,0 +1,19 @@
+//go:build windows
+
+package logger
+
+import (
+	"errors"
+	"io"
+)
+
+func init() {
+	RegisterWriter("syslog", newSyslogWriter)
+}
+
+// newSyslogWriter is a stub on platforms without log/syslog support (only
+// Windows, currently): syslog is a Unix convention and has no standard
+// equivalent there.
+func newSyslogWriter(dsn string) (io.Writer, error) {
+	return nil, errors.New("logger: syslog writer is not supported on this platform")
+}
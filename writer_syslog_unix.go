@@ -0,0 +1,66 @@
+//go:build !windows
+
+package logger
+
+import (
+	"fmt"
+	"io"
+	"log/syslog"
+)
+
+func init() {
+	RegisterWriter("syslog", newSyslogWriter)
+}
+
+// syslogFacilities maps the facility names accepted in a "syslog://" DSN's
+// "facility" query parameter to their syslog.Priority value.
+var syslogFacilities = map[string]syslog.Priority{
+	"kern":     syslog.LOG_KERN,
+	"user":     syslog.LOG_USER,
+	"mail":     syslog.LOG_MAIL,
+	"daemon":   syslog.LOG_DAEMON,
+	"auth":     syslog.LOG_AUTH,
+	"syslog":   syslog.LOG_SYSLOG,
+	"lpr":      syslog.LOG_LPR,
+	"news":     syslog.LOG_NEWS,
+	"uucp":     syslog.LOG_UUCP,
+	"cron":     syslog.LOG_CRON,
+	"authpriv": syslog.LOG_AUTHPRIV,
+	"ftp":      syslog.LOG_FTP,
+	"local0":   syslog.LOG_LOCAL0,
+	"local1":   syslog.LOG_LOCAL1,
+	"local2":   syslog.LOG_LOCAL2,
+	"local3":   syslog.LOG_LOCAL3,
+	"local4":   syslog.LOG_LOCAL4,
+	"local5":   syslog.LOG_LOCAL5,
+	"local6":   syslog.LOG_LOCAL6,
+	"local7":   syslog.LOG_LOCAL7,
+}
+
+// newSyslogWriter builds a writer over the local or remote syslog daemon
+// from a "syslog://" DSN, e.g. "syslog://localhost:514?facility=local0". An
+// empty host dials the local syslog socket. The facility defaults to
+// LOG_USER when not given, and severity is fixed at LOG_INFO since the
+// actual level is already carried in the logged JSON payload.
+func newSyslogWriter(dsn string) (io.Writer, error) {
+	var u, err = parseDSN(dsn)
+	if err != nil {
+		return nil, err
+	}
+
+	var facility = syslog.LOG_USER
+	if name := u.Query().Get("facility"); name != "" {
+		var ok bool
+		facility, ok = syslogFacilities[name]
+		if !ok {
+			return nil, fmt.Errorf("unknown syslog facility %q", name)
+		}
+	}
+
+	var priority = facility | syslog.LOG_INFO
+
+	if u.Host == "" {
+		return syslog.New(priority, "")
+	}
+	return syslog.Dial("udp", u.Host, priority, "")
+}
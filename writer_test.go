@@ -0,0 +1,55 @@
+package logger
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestRotatingFileTruncatesWithoutKeep(t *testing.T) {
+	var dir = t.TempDir()
+	var path = filepath.Join(dir, "app.log")
+
+	w, err := newFileWriter("file://" + path + "?rotate=10B")
+	if err != nil {
+		t.Fatalf("newFileWriter: %v", err)
+	}
+
+	for i := 0; i < 5; i++ {
+		if _, err := w.Write([]byte("0123456789")); err != nil {
+			t.Fatalf("write %d: %v", i, err)
+		}
+	}
+
+	info, err := os.Stat(path)
+	if err != nil {
+		t.Fatalf("stat: %v", err)
+	}
+	if info.Size() > 10 {
+		t.Fatalf("expected rotation to keep the file at or under 10 bytes, got %d bytes", info.Size())
+	}
+
+	if _, err := os.Stat(path + ".1"); !os.IsNotExist(err) {
+		t.Fatalf("expected no backup file when keep is unset, got err=%v", err)
+	}
+}
+
+func TestRotatingFileKeepsBackups(t *testing.T) {
+	var dir = t.TempDir()
+	var path = filepath.Join(dir, "app.log")
+
+	w, err := newFileWriter("file://" + path + "?rotate=10B&keep=2")
+	if err != nil {
+		t.Fatalf("newFileWriter: %v", err)
+	}
+
+	for i := 0; i < 3; i++ {
+		if _, err := w.Write([]byte("0123456789")); err != nil {
+			t.Fatalf("write %d: %v", i, err)
+		}
+	}
+
+	if _, err := os.Stat(path + ".1"); err != nil {
+		t.Fatalf("expected a .1 backup file: %v", err)
+	}
+}